@@ -1,6 +1,7 @@
 package auth0
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"time"
@@ -33,6 +34,9 @@ func NewKeyProvider(key interface{}) SecretProvider {
 
 var (
 	ErrNoJWTHeaders = errors.New("No headers in the token")
+	// ErrInvalidAlgorithm is returned when a token's header algorithm
+	// doesn't match the configured signing algorithm.
+	ErrInvalidAlgorithm = errors.New("auth0: invalid algorithm")
 )
 
 // Configuration contains
@@ -42,15 +46,79 @@ type Configuration struct {
 	secretProvider SecretProvider
 	expectedClaims jwt.Expected
 	signIn         jose.SignatureAlgorithm
+	tokens         []TokenDef
+	customClaims   func() CustomClaims
+	clockSkew      time.Duration
+}
+
+// TokenDef pairs a SecretProvider with the signing algorithm and
+// expected claims it should be used for. Configuration accepts a set
+// of these via NewConfigurationWithTokens to support key and
+// algorithm rotation, or multi-tenant setups, without standing up a
+// separate validator per definition.
+type TokenDef struct {
+	Algorithm      jose.SignatureAlgorithm
+	KeyID          string
+	SecretProvider SecretProvider
+	Issuer         string
+	Audience       []string
+}
+
+// NewConfigurationWithTokens creates a configuration that accepts any
+// of the given token definitions. ValidateRequest matches an incoming
+// token to a definition by its header's algorithm (and KeyID, when
+// set) and falls through to the next matching definition if
+// validation fails, so that rotated keys or algorithms are accepted
+// without forcing callers to run parallel validators.
+func NewConfigurationWithTokens(tokens []TokenDef, opts ...ConfigurationOption) Configuration {
+	config := Configuration{tokens: tokens}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
 }
 
 // NewConfiguration creates a configuration for server
-func NewConfiguration(provider SecretProvider, audience []string, issuer string, method jose.SignatureAlgorithm) Configuration {
-	return Configuration{
+func NewConfiguration(provider SecretProvider, audience []string, issuer string, method jose.SignatureAlgorithm, opts ...ConfigurationOption) Configuration {
+	config := Configuration{
 		secretProvider: provider,
 		expectedClaims: jwt.Expected{Issuer: issuer, Audience: audience},
 		signIn:         method,
 	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// ConfigurationOption configures optional behavior on a Configuration,
+// to be passed to NewConfiguration.
+type ConfigurationOption func(*Configuration)
+
+// CustomClaims lets callers validate their own claims (scopes, tenant
+// IDs, ...) as part of ValidateRequest. See WithCustomClaims.
+type CustomClaims interface {
+	Validate(ctx context.Context) error
+}
+
+// WithCustomClaims registers a factory for a CustomClaims value.
+// ValidateRequest unmarshals the token's claims into the returned
+// value and calls its Validate method, in addition to the built-in
+// iss/aud/time checks.
+func WithCustomClaims(f func() CustomClaims) ConfigurationOption {
+	return func(c *Configuration) {
+		c.customClaims = f
+	}
+}
+
+// WithAllowedClockSkew allows for clock drift between this server and
+// the token issuer, applied symmetrically as leeway on both the exp
+// and nbf checks (see jwt.Claims.ValidateWithLeeway). Without this
+// option, the library's 1 minute default leeway applies.
+func WithAllowedClockSkew(d time.Duration) ConfigurationOption {
+	return func(c *Configuration) {
+		c.clockSkew = d
+	}
 }
 
 // JWTValidator helps middleware
@@ -58,18 +126,22 @@ func NewConfiguration(provider SecretProvider, audience []string, issuer string,
 type JWTValidator struct {
 	config    Configuration
 	extractor RequestTokenExtractor
+
+	// ErrorHandler is invoked by Middleware when ValidateRequest fails.
+	// Left nil, Middleware responds with a plain 401.
+	ErrorHandler ErrorHandler
 }
 
 // NewValidator creates a new
 // validator with the provided configuration.
 func NewValidator(config Configuration) *JWTValidator {
-	return &JWTValidator{config, RequestTokenExtractorFunc(FromHeader)}
+	return &JWTValidator{config, RequestTokenExtractorFunc(FromHeader), nil}
 }
 
 // NewValidator creates a new
 // validator with the provided configuration and custom extractor
 func NewValidatorWithCustomExtractor(config Configuration, f func(r *http.Request) (*jwt.JSONWebToken, error)) *JWTValidator {
-	return &JWTValidator{config, RequestTokenExtractorFunc(f)}
+	return &JWTValidator{config, RequestTokenExtractorFunc(f), nil}
 }
 
 // ValidateRequest validates the token within
@@ -85,34 +157,125 @@ func (v *JWTValidator) ValidateRequest(r *http.Request) (*jwt.JSONWebToken, erro
 	if len(token.Headers) < 1 {
 		return nil, ErrNoJWTHeaders
 	}
-
 	header := token.Headers[0]
+
+	if len(v.config.tokens) > 0 {
+		return v.validateWithTokenDefs(r, token, header)
+	}
+
 	if header.Algorithm != string(v.config.signIn) {
 		return nil, ErrInvalidAlgorithm
 	}
 
+	return v.validate(r, token, v.config.secretProvider, v.config.expectedClaims)
+}
+
+// validateWithTokenDefs matches token to one of the configured
+// TokenDefs by algorithm and, when set, KeyID, validating against
+// each match in order until one succeeds.
+func (v *JWTValidator) validateWithTokenDefs(r *http.Request, token *jwt.JSONWebToken, header jose.Header) (*jwt.JSONWebToken, error) {
+	err := error(ErrInvalidAlgorithm)
+	for _, def := range v.config.tokens {
+		if header.Algorithm != string(def.Algorithm) {
+			continue
+		}
+		if def.KeyID != "" && header.KeyID != def.KeyID {
+			continue
+		}
+
+		expected := jwt.Expected{Issuer: def.Issuer, Audience: def.Audience}
+
+		var result *jwt.JSONWebToken
+		if result, err = v.validate(r, token, def.SecretProvider, expected); err == nil {
+			return result, nil
+		}
+	}
+	return nil, err
+}
+
+// validate runs the shared claim checks (signature, iss/aud/time,
+// custom claims) for token against a single provider/expected pair.
+func (v *JWTValidator) validate(r *http.Request, token *jwt.JSONWebToken, provider SecretProvider, expectedClaims jwt.Expected) (*jwt.JSONWebToken, error) {
 	claims := jwt.Claims{}
-	key, err := v.config.secretProvider.GetSecret(token)
+	key, err := provider.GetSecret(token)
 	if err != nil {
 		return nil, err
 	}
 
-	err = token.Claims(key, &claims)
+	if err = token.Claims(key, &claims); err != nil {
+		return nil, err
+	}
 
+	expected := expectedClaims.WithTime(time.Now())
+	if v.config.clockSkew > 0 {
+		err = claims.ValidateWithLeeway(expected, v.config.clockSkew)
+	} else {
+		err = claims.Validate(expected)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	expected := v.config.expectedClaims.WithTime(time.Now())
-	err = claims.Validate(expected)
-	return token, err
+	if v.config.customClaims != nil {
+		custom := v.config.customClaims()
+		if err = token.Claims(key, custom); err != nil {
+			return nil, err
+		}
+		if err = custom.Validate(r.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
 }
 
 // Claims unmarshall the claims of the provided token
 func (v *JWTValidator) Claims(req *http.Request, token *jwt.JSONWebToken, values interface{}) error {
-	key, err := v.config.secretProvider.GetSecret(token)
+	providers, err := v.providersFor(token)
 	if err != nil {
 		return err
 	}
-	return token.Claims(key, values)
+
+	for _, provider := range providers {
+		var key interface{}
+		if key, err = provider.GetSecret(token); err != nil {
+			continue
+		}
+		if err = token.Claims(key, values); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// providersFor resolves the SecretProviders that apply to token,
+// mirroring the matching ValidateRequest performs: with a single
+// Configuration it's the configured secretProvider, with
+// NewConfigurationWithTokens it's the SecretProvider of every TokenDef
+// whose Algorithm (and KeyID, when set) matches the token's header, in
+// order, so that Claims falls through them as ValidateRequest does.
+func (v *JWTValidator) providersFor(token *jwt.JSONWebToken) ([]SecretProvider, error) {
+	if len(v.config.tokens) == 0 {
+		return []SecretProvider{v.config.secretProvider}, nil
+	}
+
+	if len(token.Headers) < 1 {
+		return nil, ErrNoJWTHeaders
+	}
+	header := token.Headers[0]
+
+	var providers []SecretProvider
+	for _, def := range v.config.tokens {
+		if header.Algorithm != string(def.Algorithm) {
+			continue
+		}
+		if def.KeyID != "" && header.KeyID != def.KeyID {
+			continue
+		}
+		providers = append(providers, def.SecretProvider)
+	}
+	if len(providers) == 0 {
+		return nil, ErrInvalidAlgorithm
+	}
+	return providers, nil
 }
@@ -0,0 +1,99 @@
+package auth0
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func signToken(t *testing.T, alg jose.SignatureAlgorithm, key interface{}, claims jwt.Claims) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, nil)
+	if err != nil {
+		t.Fatalf("creating signer: %v", err)
+	}
+
+	raw, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return raw
+}
+
+func bearerRequest(raw string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+raw)
+	return req
+}
+
+func TestValidateRequestAllowsClockSkewOnNotBefore(t *testing.T) {
+	key := []byte("super-secret-key-for-testing-only")
+	now := time.Now()
+	claims := jwt.Claims{
+		Issuer:    "issuer",
+		NotBefore: jwt.NewNumericDate(now.Add(90 * time.Second)),
+		Expiry:    jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+	raw := signToken(t, jose.HS256, key, claims)
+
+	config := NewConfiguration(NewKeyProvider(key), nil, "issuer", jose.HS256, WithAllowedClockSkew(2*time.Minute))
+	validator := NewValidator(config)
+
+	if _, err := validator.ValidateRequest(bearerRequest(raw)); err != nil {
+		t.Fatalf("expected a token with nbf inside the configured clock skew to validate, got: %v", err)
+	}
+}
+
+func TestValidateRequestRejectsNotBeforeOutsideClockSkew(t *testing.T) {
+	key := []byte("super-secret-key-for-testing-only")
+	now := time.Now()
+	claims := jwt.Claims{
+		Issuer:    "issuer",
+		NotBefore: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		Expiry:    jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+	raw := signToken(t, jose.HS256, key, claims)
+
+	config := NewConfiguration(NewKeyProvider(key), nil, "issuer", jose.HS256, WithAllowedClockSkew(2*time.Minute))
+	validator := NewValidator(config)
+
+	if _, err := validator.ValidateRequest(bearerRequest(raw)); err == nil {
+		t.Fatal("expected a token with nbf outside the configured clock skew to be rejected")
+	}
+}
+
+func TestClaimsResolvesProviderFromMatchedTokenDef(t *testing.T) {
+	key := []byte("another-test-signing-key")
+	now := time.Now()
+	claims := jwt.Claims{
+		Issuer:    "issuer",
+		Subject:   "user-123",
+		NotBefore: jwt.NewNumericDate(now.Add(-time.Minute)),
+		Expiry:    jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+	raw := signToken(t, jose.HS256, key, claims)
+
+	config := NewConfigurationWithTokens([]TokenDef{
+		{Algorithm: jose.HS256, SecretProvider: NewKeyProvider(key), Issuer: "issuer"},
+	})
+	validator := NewValidator(config)
+	req := bearerRequest(raw)
+
+	token, err := validator.ValidateRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	var out jwt.Claims
+	if err := validator.Claims(req, token, &out); err != nil {
+		t.Fatalf("Claims should resolve the matched TokenDef's provider, got: %v", err)
+	}
+	if out.Subject != "user-123" {
+		t.Fatalf("expected subject %q, got %q", "user-123", out.Subject)
+	}
+}
@@ -0,0 +1,84 @@
+package auth0
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// ErrNoJWTFound is returned by the bundled extractors when they can't
+// locate a token in the request.
+var ErrNoJWTFound = errors.New("No JWT found in request")
+
+// RequestTokenExtractor extracts a JSONWebToken from an http.Request.
+type RequestTokenExtractor interface {
+	Extract(r *http.Request) (*jwt.JSONWebToken, error)
+}
+
+// RequestTokenExtractorFunc wraps a plain function so it implements
+// RequestTokenExtractor.
+type RequestTokenExtractorFunc func(r *http.Request) (*jwt.JSONWebToken, error)
+
+// Extract implements RequestTokenExtractor.
+func (f RequestTokenExtractorFunc) Extract(r *http.Request) (*jwt.JSONWebToken, error) {
+	return f(r)
+}
+
+// FromHeader extracts a bearer token from the Authorization header.
+func FromHeader(r *http.Request) (*jwt.JSONWebToken, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, ErrNoJWTFound
+	}
+
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return nil, ErrNoJWTFound
+	}
+
+	return jwt.ParseSigned(parts[1])
+}
+
+// FromQuery returns a RequestTokenExtractor that reads the token from
+// the named URL query parameter, e.g. FromQuery("jwt") for "?jwt=...".
+func FromQuery(param string) RequestTokenExtractor {
+	return RequestTokenExtractorFunc(func(r *http.Request) (*jwt.JSONWebToken, error) {
+		raw := r.URL.Query().Get(param)
+		if raw == "" {
+			return nil, ErrNoJWTFound
+		}
+		return jwt.ParseSigned(raw)
+	})
+}
+
+// FromCookie returns a RequestTokenExtractor that reads the token from
+// the named cookie.
+func FromCookie(name string) RequestTokenExtractor {
+	return RequestTokenExtractorFunc(func(r *http.Request) (*jwt.JSONWebToken, error) {
+		cookie, err := r.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return nil, ErrNoJWTFound
+		}
+		return jwt.ParseSigned(cookie.Value)
+	})
+}
+
+// FromFirst tries each extractor in order and returns the token from
+// the first one that finds a match, mirroring the lookup order used
+// by jwtauth-style middleware (header, then query, then cookie, ...).
+// If none of them find a token, it returns the error from the last
+// extractor tried.
+func FromFirst(extractors ...RequestTokenExtractor) RequestTokenExtractor {
+	return RequestTokenExtractorFunc(func(r *http.Request) (*jwt.JSONWebToken, error) {
+		var err error
+		for _, e := range extractors {
+			var token *jwt.JSONWebToken
+			if token, err = e.Extract(r); err == nil {
+				return token, nil
+			}
+		}
+		return nil, err
+	})
+}
@@ -0,0 +1,88 @@
+package auth0
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestFromQuery(t *testing.T) {
+	raw := signToken(t, jose.HS256, []byte("key"), jwt.Claims{Subject: "q"})
+
+	req := httptest.NewRequest(http.MethodGet, "/?jwt="+raw, nil)
+	if _, err := FromQuery("jwt").Extract(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := FromQuery("jwt").Extract(req); err != ErrNoJWTFound {
+		t.Fatalf("expected ErrNoJWTFound for a missing query param, got %v", err)
+	}
+}
+
+func TestFromCookie(t *testing.T) {
+	raw := signToken(t, jose.HS256, []byte("key"), jwt.Claims{Subject: "c"})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: raw})
+	if _, err := FromCookie("session").Extract(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := FromCookie("session").Extract(req); err != ErrNoJWTFound {
+		t.Fatalf("expected ErrNoJWTFound for a missing cookie, got %v", err)
+	}
+}
+
+func TestFromFirstTriesExtractorsInPriorityOrder(t *testing.T) {
+	extractor := FromFirst(RequestTokenExtractorFunc(FromHeader), FromQuery("jwt"), FromCookie("session"))
+	queryRaw := signToken(t, jose.HS256, []byte("key"), jwt.Claims{Subject: "query"})
+	headerRaw := signToken(t, jose.HS256, []byte("key"), jwt.Claims{Subject: "header"})
+
+	// Only the query parameter is set: it's used as a fallback.
+	req := httptest.NewRequest(http.MethodGet, "/?jwt="+queryRaw, nil)
+	token, err := extractor.Extract(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSubject(t, token, "query")
+
+	// Both header and query are set: the header wins.
+	req = httptest.NewRequest(http.MethodGet, "/?jwt="+queryRaw, nil)
+	req.Header.Set("Authorization", "Bearer "+headerRaw)
+	token, err = extractor.Extract(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSubject(t, token, "header")
+
+	// Nothing set: every extractor misses.
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := extractor.Extract(req); err != ErrNoJWTFound {
+		t.Fatalf("expected ErrNoJWTFound when no source has a token, got %v", err)
+	}
+}
+
+func TestFromHeaderRejectsMalformedHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "not-a-bearer-token")
+	if _, err := FromHeader(req); err != ErrNoJWTFound {
+		t.Fatalf("expected ErrNoJWTFound for a malformed header, got %v", err)
+	}
+}
+
+func assertSubject(t *testing.T, token *jwt.JSONWebToken, want string) {
+	t.Helper()
+
+	var claims jwt.Claims
+	if err := token.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		t.Fatalf("unexpected error reading claims: %v", err)
+	}
+	if claims.Subject != want {
+		t.Fatalf("expected subject %q, got %q", want, claims.Subject)
+	}
+}
@@ -0,0 +1,288 @@
+package auth0
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// defaultIntrospectionTimeout bounds how long a single call to the
+// introspection endpoint may take.
+const defaultIntrospectionTimeout = 10 * time.Second
+
+var (
+	// ErrTokenInactive is returned when the introspection endpoint
+	// reports the token as no longer active (expired, revoked, ...).
+	ErrTokenInactive = errors.New("auth0: token is not active")
+	// ErrTokenExpired is returned when the introspected token's own
+	// exp claim is in the past.
+	ErrTokenExpired = errors.New("auth0: token is expired")
+	// ErrInvalidIssuer is returned when the introspected token's
+	// issuer doesn't match the configured one.
+	ErrInvalidIssuer = errors.New("auth0: invalid issuer")
+	// ErrInvalidAudience is returned when the introspected token's
+	// audience doesn't include the configured one.
+	ErrInvalidAudience = errors.New("auth0: invalid audience")
+)
+
+// IntrospectionResult is the RFC 7662 token introspection response,
+// treated as the source of truth in place of local signature
+// verification.
+type IntrospectionResult struct {
+	Active    bool         `json:"active"`
+	Scope     string       `json:"scope,omitempty"`
+	ClientID  string       `json:"client_id,omitempty"`
+	Username  string       `json:"username,omitempty"`
+	TokenType string       `json:"token_type,omitempty"`
+	Exp       int64        `json:"exp,omitempty"`
+	Iat       int64        `json:"iat,omitempty"`
+	Nbf       int64        `json:"nbf,omitempty"`
+	Sub       string       `json:"sub,omitempty"`
+	Aud       jwt.Audience `json:"aud,omitempty"`
+	Iss       string       `json:"iss,omitempty"`
+}
+
+// IntrospectionOption configures an IntrospectionValidator created by
+// NewIntrospectionValidator.
+type IntrospectionOption func(*IntrospectionValidator)
+
+// WithIntrospectionHTTPClient lets the caller provide its own
+// *http.Client for calls to the introspection endpoint.
+func WithIntrospectionHTTPClient(client *http.Client) IntrospectionOption {
+	return func(v *IntrospectionValidator) {
+		v.client = client
+	}
+}
+
+// WithIntrospectionTimeout bounds how long a single introspection
+// request may take.
+func WithIntrospectionTimeout(timeout time.Duration) IntrospectionOption {
+	return func(v *IntrospectionValidator) {
+		v.timeout = timeout
+	}
+}
+
+// WithIntrospectionCache caches introspection responses keyed by a
+// hash of the token, for up to ttl or the token's own exp, whichever
+// comes first.
+func WithIntrospectionCache(ttl time.Duration) IntrospectionOption {
+	return func(v *IntrospectionValidator) {
+		v.cacheTTL = ttl
+	}
+}
+
+// WithIntrospectionAudience requires the introspected token's aud
+// claim to contain at least one of the given values.
+func WithIntrospectionAudience(audience ...string) IntrospectionOption {
+	return func(v *IntrospectionValidator) {
+		v.audience = audience
+	}
+}
+
+// WithIntrospectionIssuer requires the introspected token's iss claim
+// to match the given value.
+func WithIntrospectionIssuer(issuer string) IntrospectionOption {
+	return func(v *IntrospectionValidator) {
+		v.issuer = issuer
+	}
+}
+
+// IntrospectionValidator validates opaque access tokens against an
+// RFC 7662 introspection endpoint instead of verifying a JWT
+// signature locally.
+type IntrospectionValidator struct {
+	endpoint     string
+	clientID     string
+	clientSecret string
+	audience     []string
+	issuer       string
+	client       *http.Client
+	timeout      time.Duration
+	cacheTTL     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedIntrospection
+}
+
+type cachedIntrospection struct {
+	result    IntrospectionResult
+	expiresAt time.Time
+}
+
+// NewIntrospectionValidator creates an IntrospectionValidator that
+// authenticates against endpoint using clientID/clientSecret.
+func NewIntrospectionValidator(endpoint string, clientID, clientSecret string, opts ...IntrospectionOption) *IntrospectionValidator {
+	v := &IntrospectionValidator{
+		endpoint:     endpoint,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       http.DefaultClient,
+		timeout:      defaultIntrospectionTimeout,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// ValidateRequest extracts the bearer token from r and introspects it,
+// returning the introspection result in place of a parsed JWT.
+func (v *IntrospectionValidator) ValidateRequest(r *http.Request) (*IntrospectionResult, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := v.introspect(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Active {
+		return nil, ErrTokenInactive
+	}
+
+	if result.Exp > 0 && time.Now().After(time.Unix(result.Exp, 0)) {
+		return nil, ErrTokenExpired
+	}
+
+	if v.issuer != "" && result.Iss != v.issuer {
+		return nil, ErrInvalidIssuer
+	}
+
+	if len(v.audience) > 0 {
+		ok := false
+		for _, aud := range v.audience {
+			if result.Aud.Contains(aud) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return nil, ErrInvalidAudience
+		}
+	}
+
+	return &result, nil
+}
+
+// introspect returns the introspection result for token, serving it
+// from cache when caching is enabled and a fresh entry exists.
+func (v *IntrospectionValidator) introspect(token string) (IntrospectionResult, error) {
+	if v.cacheTTL > 0 {
+		if result, ok := v.fromCache(token); ok {
+			return result, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return IntrospectionResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(v.clientID, v.clientSecret)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return IntrospectionResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return IntrospectionResult{}, fmt.Errorf("auth0: introspection endpoint returned status %d", resp.StatusCode)
+	}
+
+	var result IntrospectionResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return IntrospectionResult{}, fmt.Errorf("auth0: decoding introspection response: %w", err)
+	}
+
+	if v.cacheTTL > 0 {
+		v.storeCache(token, result)
+	}
+	return result, nil
+}
+
+func (v *IntrospectionValidator) fromCache(token string) (IntrospectionResult, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.cache[introspectionCacheKey(token)]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return IntrospectionResult{}, false
+	}
+	return entry.result, true
+}
+
+func (v *IntrospectionValidator) storeCache(token string, result IntrospectionResult) {
+	ttl := v.cacheTTL
+	if result.Exp > 0 {
+		if untilExp := time.Until(time.Unix(result.Exp, 0)); untilExp < ttl {
+			ttl = untilExp
+		}
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.cache == nil {
+		v.cache = make(map[string]cachedIntrospection)
+	}
+
+	// Sweep expired entries on every write so the cache stays bounded
+	// by the number of currently-valid tokens rather than growing
+	// forever as distinct tokens are introspected over time.
+	v.evictExpiredLocked()
+
+	v.cache[introspectionCacheKey(token)] = cachedIntrospection{result: result, expiresAt: time.Now().Add(ttl)}
+}
+
+// evictExpiredLocked removes expired entries from the cache. Callers
+// must hold v.mu.
+func (v *IntrospectionValidator) evictExpiredLocked() {
+	now := time.Now()
+	for key, entry := range v.cache {
+		if now.After(entry.expiresAt) {
+			delete(v.cache, key)
+		}
+	}
+}
+
+// introspectionCacheKey hashes the token so the cache never holds raw
+// bearer tokens in memory.
+func introspectionCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// bearerToken extracts the raw bearer token from the Authorization
+// header, without attempting to parse it as a JWT.
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrNoJWTFound
+	}
+
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", ErrNoJWTFound
+	}
+
+	return parts[1], nil
+}
@@ -0,0 +1,134 @@
+package auth0
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func newIntrospectionServer(t *testing.T, respond func(token string) IntrospectionResult) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parsing introspection request: %v", err)
+		}
+		if err := json.NewEncoder(w).Encode(respond(r.PostForm.Get("token"))); err != nil {
+			t.Fatalf("encoding introspection response: %v", err)
+		}
+	}))
+}
+
+func introspectionRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestIntrospectionValidatorRejectsInactiveToken(t *testing.T) {
+	server := newIntrospectionServer(t, func(string) IntrospectionResult {
+		return IntrospectionResult{Active: false}
+	})
+	defer server.Close()
+
+	validator := NewIntrospectionValidator(server.URL, "client", "secret")
+	if _, err := validator.ValidateRequest(introspectionRequest("opaque-token")); err != ErrTokenInactive {
+		t.Fatalf("expected ErrTokenInactive, got %v", err)
+	}
+}
+
+func TestIntrospectionValidatorRejectsExpiredToken(t *testing.T) {
+	server := newIntrospectionServer(t, func(string) IntrospectionResult {
+		return IntrospectionResult{Active: true, Exp: time.Now().Add(-time.Minute).Unix()}
+	})
+	defer server.Close()
+
+	validator := NewIntrospectionValidator(server.URL, "client", "secret")
+	if _, err := validator.ValidateRequest(introspectionRequest("opaque-token")); err != ErrTokenExpired {
+		t.Fatalf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestIntrospectionValidatorChecksIssuerAndAudience(t *testing.T) {
+	server := newIntrospectionServer(t, func(string) IntrospectionResult {
+		return IntrospectionResult{
+			Active: true,
+			Iss:    "https://issuer.example",
+			Aud:    jwt.Audience{"expected-aud"},
+			Exp:    time.Now().Add(time.Hour).Unix(),
+		}
+	})
+	defer server.Close()
+
+	matching := NewIntrospectionValidator(server.URL, "client", "secret",
+		WithIntrospectionIssuer("https://issuer.example"),
+		WithIntrospectionAudience("expected-aud"))
+	if _, err := matching.ValidateRequest(introspectionRequest("opaque-token")); err != nil {
+		t.Fatalf("unexpected error for matching issuer/audience: %v", err)
+	}
+
+	wrongIssuer := NewIntrospectionValidator(server.URL, "client", "secret", WithIntrospectionIssuer("https://other"))
+	if _, err := wrongIssuer.ValidateRequest(introspectionRequest("opaque-token")); err != ErrInvalidIssuer {
+		t.Fatalf("expected ErrInvalidIssuer, got %v", err)
+	}
+
+	wrongAudience := NewIntrospectionValidator(server.URL, "client", "secret", WithIntrospectionAudience("other-aud"))
+	if _, err := wrongAudience.ValidateRequest(introspectionRequest("opaque-token")); err != ErrInvalidAudience {
+		t.Fatalf("expected ErrInvalidAudience, got %v", err)
+	}
+}
+
+func TestIntrospectionValidatorServesRepeatLookupsFromCache(t *testing.T) {
+	var calls int32
+	server := newIntrospectionServer(t, func(string) IntrospectionResult {
+		atomic.AddInt32(&calls, 1)
+		return IntrospectionResult{Active: true, Exp: time.Now().Add(time.Hour).Unix()}
+	})
+	defer server.Close()
+
+	validator := NewIntrospectionValidator(server.URL, "client", "secret", WithIntrospectionCache(time.Hour))
+	req := introspectionRequest("same-token")
+
+	if _, err := validator.ValidateRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := validator.ValidateRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected the second lookup to be served from cache, introspected %d times", got)
+	}
+}
+
+func TestIntrospectionValidatorEvictsExpiredCacheEntriesOnWrite(t *testing.T) {
+	server := newIntrospectionServer(t, func(string) IntrospectionResult {
+		// Far in the future, so the cache TTL (not the token's own
+		// exp) is what bounds how long entries stick around.
+		return IntrospectionResult{Active: true, Exp: time.Now().Add(time.Hour).Unix()}
+	})
+	defer server.Close()
+
+	validator := NewIntrospectionValidator(server.URL, "client", "secret", WithIntrospectionCache(10*time.Millisecond))
+
+	if _, err := validator.ValidateRequest(introspectionRequest("short-lived")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := validator.ValidateRequest(introspectionRequest("long-lived")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	validator.mu.Lock()
+	_, stillCached := validator.cache[introspectionCacheKey("short-lived")]
+	validator.mu.Unlock()
+	if stillCached {
+		t.Fatal("expected the expired short-lived entry to be evicted when a new entry was written")
+	}
+}
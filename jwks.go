@@ -0,0 +1,160 @@
+package auth0
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// defaultJWKSCacheTTL is how long a fetched JWKS is trusted
+// before it is considered stale and refreshed in the background.
+const defaultJWKSCacheTTL = 5 * time.Minute
+
+// defaultJWKSTimeout bounds how long a single JWKS fetch may take.
+const defaultJWKSTimeout = 10 * time.Second
+
+// JWKSOption configures a jwksProvider created by NewJWKSProvider.
+type JWKSOption func(*jwksProvider)
+
+// WithJWKSCacheTTL overrides the default 5 minute cache TTL, after
+// which the key set is refreshed in the background.
+func WithJWKSCacheTTL(ttl time.Duration) JWKSOption {
+	return func(p *jwksProvider) {
+		p.ttl = ttl
+	}
+}
+
+// WithJWKSHTTPClient lets the caller provide its own *http.Client,
+// e.g. to configure transport-level proxying or TLS.
+func WithJWKSHTTPClient(client *http.Client) JWKSOption {
+	return func(p *jwksProvider) {
+		p.client = client
+	}
+}
+
+// WithJWKSTimeout bounds how long a single fetch of the JWKS
+// endpoint may take before its context is cancelled.
+func WithJWKSTimeout(timeout time.Duration) JWKSOption {
+	return func(p *jwksProvider) {
+		p.timeout = timeout
+	}
+}
+
+// jwksProvider is a SecretProvider that resolves the signing key for
+// a token by fetching a JSON Web Key Set and matching it against the
+// token's `kid` header.
+type jwksProvider struct {
+	jwksURL string
+	client  *http.Client
+	ttl     time.Duration
+	timeout time.Duration
+
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	keys      jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// NewJWKSProvider returns a SecretProvider that fetches its keys from
+// the JWKS document at jwksURL and selects the key matching the
+// incoming token's `kid` header. The key set is cached for 5 minutes
+// by default and refreshed in the background once stale; a `kid` that
+// isn't found in the cache triggers an immediate synchronous refresh
+// so that freshly rotated keys are picked up without a restart.
+func NewJWKSProvider(jwksURL string, opts ...JWKSOption) SecretProvider {
+	p := &jwksProvider{
+		jwksURL: jwksURL,
+		client:  http.DefaultClient,
+		ttl:     defaultJWKSCacheTTL,
+		timeout: defaultJWKSTimeout,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GetSecret implements SecretProvider.
+func (p *jwksProvider) GetSecret(token *jwt.JSONWebToken) (interface{}, error) {
+	if len(token.Headers) < 1 {
+		return nil, ErrNoJWTHeaders
+	}
+	kid := token.Headers[0].KeyID
+
+	if key, ok := p.keyFor(kid); ok {
+		return key, nil
+	}
+
+	// The kid wasn't in the cache: it may have just rotated in, so
+	// force a synchronous refresh before giving up.
+	if err := p.refresh(); err != nil {
+		return nil, err
+	}
+	if key, ok := p.keyFor(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("auth0: no key found in JWKS for kid %q", kid)
+}
+
+// keyFor returns the cached key matching kid, if any, kicking off a
+// background refresh first if the cache has gone stale.
+func (p *jwksProvider) keyFor(kid string) (interface{}, bool) {
+	p.mu.RLock()
+	keys, fetchedAt := p.keys, p.fetchedAt
+	p.mu.RUnlock()
+
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) > p.ttl {
+		go p.refresh()
+	}
+
+	for _, k := range keys.Keys {
+		if k.KeyID == kid {
+			return k.Key, true
+		}
+	}
+	return nil, false
+}
+
+// refresh fetches the JWKS document and replaces the cached key set.
+// Concurrent refreshes are serialized so a background refresh and a
+// kid-miss refresh never race each other.
+func (p *jwksProvider) refresh() error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.jwksURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth0: fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return fmt.Errorf("auth0: decoding JWKS: %w", err)
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	p.mu.Unlock()
+	return nil
+}
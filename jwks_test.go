@@ -0,0 +1,121 @@
+package auth0
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func signTokenWithKid(t *testing.T, alg jose.SignatureAlgorithm, key interface{}, kid string, claims jwt.Claims) string {
+	t.Helper()
+
+	opts := (&jose.SignerOptions{}).WithHeader("kid", kid)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, opts)
+	if err != nil {
+		t.Fatalf("creating signer: %v", err)
+	}
+
+	raw, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return raw
+}
+
+func TestJWKSProviderResolvesKeyByKid(t *testing.T) {
+	key := []byte("jwks-test-key-one")
+	jwk := jose.JSONWebKey{Key: key, KeyID: "kid-1", Algorithm: string(jose.HS256), Use: "sig"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk}})
+	}))
+	defer server.Close()
+
+	provider := NewJWKSProvider(server.URL)
+
+	raw := signTokenWithKid(t, jose.HS256, key, "kid-1", jwt.Claims{Subject: "user"})
+	token, err := jwt.ParseSigned(raw)
+	if err != nil {
+		t.Fatalf("parsing token: %v", err)
+	}
+
+	secret, err := provider.GetSecret(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(secret.([]byte)) != string(key) {
+		t.Fatalf("expected resolved key to match, got %v", secret)
+	}
+}
+
+func TestJWKSProviderRefreshesOnKidMiss(t *testing.T) {
+	oldKey := []byte("jwks-old-key")
+	newKey := []byte("jwks-new-key")
+	oldJWK := jose.JSONWebKey{Key: oldKey, KeyID: "old", Algorithm: string(jose.HS256), Use: "sig"}
+	newJWK := jose.JSONWebKey{Key: newKey, KeyID: "new", Algorithm: string(jose.HS256), Use: "sig"}
+
+	var rotated int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := []jose.JSONWebKey{oldJWK}
+		if atomic.LoadInt32(&rotated) == 1 {
+			keys = []jose.JSONWebKey{newJWK}
+		}
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: keys})
+	}))
+	defer server.Close()
+
+	provider := NewJWKSProvider(server.URL, WithJWKSCacheTTL(time.Hour))
+
+	// Prime the cache with the old key set.
+	primed := signTokenWithKid(t, jose.HS256, oldKey, "old", jwt.Claims{Subject: "user"})
+	token, err := jwt.ParseSigned(primed)
+	if err != nil {
+		t.Fatalf("parsing token: %v", err)
+	}
+	if _, err := provider.GetSecret(token); err != nil {
+		t.Fatalf("priming cache: %v", err)
+	}
+
+	// Rotate the JWKS endpoint and sign with the new kid: the cached
+	// key set doesn't have it, even though the TTL hasn't elapsed, so
+	// this must force a synchronous refresh rather than fail outright.
+	atomic.StoreInt32(&rotated, 1)
+	raw := signTokenWithKid(t, jose.HS256, newKey, "new", jwt.Claims{Subject: "user"})
+	token, err = jwt.ParseSigned(raw)
+	if err != nil {
+		t.Fatalf("parsing token: %v", err)
+	}
+
+	secret, err := provider.GetSecret(token)
+	if err != nil {
+		t.Fatalf("expected kid miss to trigger a refresh, got error: %v", err)
+	}
+	if string(secret.([]byte)) != string(newKey) {
+		t.Fatalf("expected the rotated key to be resolved, got %v", secret)
+	}
+}
+
+func TestJWKSProviderReturnsErrorOnNon200Response(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	provider := NewJWKSProvider(server.URL)
+
+	raw := signTokenWithKid(t, jose.HS256, []byte("whatever"), "missing", jwt.Claims{Subject: "user"})
+	token, err := jwt.ParseSigned(raw)
+	if err != nil {
+		t.Fatalf("parsing token: %v", err)
+	}
+
+	if _, err := provider.GetSecret(token); err == nil {
+		t.Fatal("expected an error when the JWKS endpoint returns a non-200 status")
+	}
+}
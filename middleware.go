@@ -0,0 +1,73 @@
+package auth0
+
+import (
+	"context"
+	"net/http"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// contextKey is an unexported type so that auth0's context keys never
+// collide with keys set by other packages.
+type contextKey struct{ name string }
+
+var (
+	// TokenCtxKey is the context key under which Middleware stores the
+	// verified *jwt.JSONWebToken.
+	TokenCtxKey = &contextKey{"auth0 token"}
+	// ClaimsCtxKey is the context key under which Middleware stores the
+	// decoded Claims.
+	ClaimsCtxKey = &contextKey{"auth0 claims"}
+)
+
+// Claims is the decoded set of claims Middleware stores on the
+// request context alongside the verified token.
+type Claims map[string]interface{}
+
+// ErrorHandler handles a request whose token failed validation in
+// Middleware. The default writes a 401 response with the error text.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// Middleware returns a func(http.Handler) http.Handler that validates
+// the request's token before calling next. On success, the verified
+// token and its decoded claims are stored on the request context
+// (retrievable with FromContext) before next is called. On failure,
+// ErrorHandler is invoked instead of next.
+func (v *JWTValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := v.ValidateRequest(r)
+		if err != nil {
+			v.handleError(w, r, err)
+			return
+		}
+
+		claims := Claims{}
+		if err := v.Claims(r, token, &claims); err != nil {
+			v.handleError(w, r, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), TokenCtxKey, token)
+		ctx = context.WithValue(ctx, ClaimsCtxKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (v *JWTValidator) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if v.ErrorHandler != nil {
+		v.ErrorHandler(w, r, err)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusUnauthorized)
+}
+
+// FromContext returns the verified token and decoded claims stored by
+// Middleware, and whether a token was found.
+func FromContext(ctx context.Context) (*jwt.JSONWebToken, Claims, bool) {
+	token, ok := ctx.Value(TokenCtxKey).(*jwt.JSONWebToken)
+	if !ok {
+		return nil, nil, false
+	}
+	claims, _ := ctx.Value(ClaimsCtxKey).(Claims)
+	return token, claims, true
+}
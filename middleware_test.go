@@ -0,0 +1,86 @@
+package auth0
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestMiddlewareStoresTokenAndClaimsOnContext(t *testing.T) {
+	key := []byte("middleware-test-key")
+	now := time.Now()
+	raw := signToken(t, jose.HS256, key, jwt.Claims{
+		Issuer:  "issuer",
+		Subject: "user-42",
+		Expiry:  jwt.NewNumericDate(now.Add(time.Hour)),
+	})
+
+	config := NewConfiguration(NewKeyProvider(key), nil, "issuer", jose.HS256)
+	validator := NewValidator(config)
+
+	var gotToken *jwt.JSONWebToken
+	var gotClaims Claims
+	var ok bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken, gotClaims, ok = FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	validator.Middleware(next).ServeHTTP(rec, bearerRequest(raw))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected next to be called with 200, got %d", rec.Code)
+	}
+	if !ok || gotToken == nil {
+		t.Fatal("expected FromContext to return the verified token")
+	}
+	if gotClaims["sub"] != "user-42" {
+		t.Fatalf("expected sub claim %q, got %v", "user-42", gotClaims["sub"])
+	}
+}
+
+func TestMiddlewareDefaultErrorHandlerReturns401(t *testing.T) {
+	config := NewConfiguration(NewKeyProvider([]byte("key")), nil, "issuer", jose.HS256)
+	validator := NewValidator(config)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next should not be called when validation fails")
+	})
+
+	rec := httptest.NewRecorder()
+	validator.Middleware(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the default ErrorHandler to respond 401, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareCustomErrorHandler(t *testing.T) {
+	config := NewConfiguration(NewKeyProvider([]byte("key")), nil, "issuer", jose.HS256)
+	validator := NewValidator(config)
+
+	var handlerCalled bool
+	validator.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		handlerCalled = true
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next should not be called when validation fails")
+	})
+
+	rec := httptest.NewRecorder()
+	validator.Middleware(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !handlerCalled {
+		t.Fatal("expected the custom ErrorHandler to be invoked")
+	}
+	if rec.Code != http.StatusTeapot {
+		t.Fatalf("expected the custom ErrorHandler's response code, got %d", rec.Code)
+	}
+}